@@ -0,0 +1,158 @@
+package reviser
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/incu6us/goimports-reviser/v3/pkg/astutil"
+)
+
+// ErrFailFastSkipped is the Result.Err value for a path a Batch never ran
+// Fix on because FailFast had already stopped the run. Without this, a
+// caller counting processed files can't tell a skipped path from one that
+// was silently lost.
+var ErrFailFastSkipped = errors.New("reviser: skipped, fail-fast triggered by a prior error")
+
+// PackageDependencyResolver resolves the import-path -> package-name map for
+// a directory, the same information astutil.LoadPackageDependencies
+// provides. SourceFile depends on this interface, instead of calling the
+// package function directly, so a Batch can inject a shared cache and so
+// tests can stub dependency resolution entirely.
+type PackageDependencyResolver interface {
+	LoadPackageDependencies(dir, buildTag string) (map[string]string, error)
+}
+
+// dependencyCacheKey identifies one resolved package-dependency graph: a
+// directory plus the build-tag configuration it was resolved under.
+type dependencyCacheKey struct {
+	dir      string
+	buildTag string
+}
+
+// dependencyCache memoizes astutil.LoadPackageDependencies results so a
+// Batch run across many files in the same package/build-tag only pays the
+// resolution cost once, instead of once per file.
+type dependencyCache struct {
+	mu    sync.Mutex
+	byKey map[dependencyCacheKey]*dependencyCacheEntry
+}
+
+// dependencyCacheEntry resolves its key at most once, even when several
+// workers ask for the same key at the same time.
+type dependencyCacheEntry struct {
+	once     sync.Once
+	resolved map[string]string
+	err      error
+}
+
+func newDependencyCache() *dependencyCache {
+	return &dependencyCache{byKey: map[dependencyCacheKey]*dependencyCacheEntry{}}
+}
+
+func (c *dependencyCache) LoadPackageDependencies(dir, buildTag string) (map[string]string, error) {
+	key := dependencyCacheKey{dir: dir, buildTag: buildTag}
+
+	c.mu.Lock()
+	entry, ok := c.byKey[key]
+	if !ok {
+		entry = &dependencyCacheEntry{}
+		c.byKey[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.resolved, entry.err = astutil.LoadPackageDependencies(dir, buildTag)
+	})
+
+	return entry.resolved, entry.err
+}
+
+// Result is what Batch reports for one processed file.
+type Result struct {
+	FilePath string
+	Content  []byte
+	Changed  bool
+	Err      error
+}
+
+// Batch fixes many files concurrently, sharing one dependency cache across
+// files so astutil.LoadPackageDependencies only runs once per (dir,
+// buildTag) pair instead of once per file - the cost that dominates runtime
+// on large repos processed serially.
+type Batch struct {
+	// ProjectName and Options configure every SourceFile the batch creates,
+	// same as NewSourceFile/Fix for a single file.
+	ProjectName string
+	Options     []SourceFileOption
+
+	// Concurrency caps how many files are fixed at once. Zero means
+	// runtime.GOMAXPROCS(0).
+	Concurrency int
+
+	// FailFast stops running Fix on new files once one has errored. Paths
+	// already in flight finish normally; every other dispatched path still
+	// gets a Result, with Err set to ErrFailFastSkipped, so a caller can
+	// always tell a skip from a loss.
+	FailFast bool
+
+	cache *dependencyCache
+}
+
+// Run fixes every path in filePaths, streaming a Result per file over the
+// returned channel as soon as it's done. The channel is closed once every
+// dispatched file has been processed.
+func (b *Batch) Run(filePaths []string) <-chan Result {
+	if b.cache == nil {
+		b.cache = newDependencyCache()
+	}
+
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	paths := make(chan string)
+	results := make(chan Result)
+
+	var stopped int32
+
+	var workers sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+
+			for filePath := range paths {
+				if b.FailFast && atomic.LoadInt32(&stopped) != 0 {
+					results <- Result{FilePath: filePath, Err: ErrFailFastSkipped}
+					continue
+				}
+
+				sf := NewSourceFile(b.ProjectName, filePath)
+				sf.dependencyResolver = b.cache
+
+				content, changed, err := sf.Fix(b.Options...)
+
+				results <- Result{FilePath: filePath, Content: content, Changed: changed, Err: err}
+
+				if err != nil && b.FailFast {
+					atomic.StoreInt32(&stopped, 1)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for _, filePath := range filePaths {
+			paths <- filePath
+		}
+		close(paths)
+
+		workers.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,70 @@
+package reviser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchFailFastSkipsWithoutDroppingResults(t *testing.T) {
+	b := &Batch{Concurrency: 1, FailFast: true}
+
+	paths := []string{
+		"testdata/does-not-exist-0.go",
+		"testdata/does-not-exist-1.go",
+		"testdata/does-not-exist-2.go",
+		"testdata/does-not-exist-3.go",
+	}
+
+	results := map[string]Result{}
+	for result := range b.Run(paths) {
+		results[result.FilePath] = result
+	}
+
+	if len(results) != len(paths) {
+		t.Fatalf("got %d results, want one per dispatched path (%d)", len(results), len(paths))
+	}
+
+	var skipped int
+	for _, path := range paths {
+		result, ok := results[path]
+		if !ok {
+			t.Errorf("no Result for %s", path)
+			continue
+		}
+		if result.Err == nil {
+			t.Errorf("Result for %s has no error, want either the read failure or ErrFailFastSkipped", path)
+			continue
+		}
+		if errors.Is(result.Err, ErrFailFastSkipped) {
+			skipped++
+		}
+	}
+
+	// Concurrency: 1 guarantees the first path's read failure trips FailFast
+	// before any later path is dispatched, so every path after it must come
+	// back as a skip rather than being silently dropped.
+	if skipped != len(paths)-1 {
+		t.Errorf("got %d skipped results, want %d (all but the first failing path)", skipped, len(paths)-1)
+	}
+}
+
+func TestBatchWithoutFailFastRunsEveryPath(t *testing.T) {
+	b := &Batch{Concurrency: 2, FailFast: false}
+
+	paths := []string{
+		"testdata/does-not-exist-0.go",
+		"testdata/does-not-exist-1.go",
+	}
+
+	var count int
+	for result := range b.Run(paths) {
+		if errors.Is(result.Err, ErrFailFastSkipped) {
+			t.Errorf("got ErrFailFastSkipped for %s with FailFast disabled", result.FilePath)
+		}
+		count++
+	}
+
+	if count != len(paths) {
+		t.Fatalf("got %d results, want %d", count, len(paths))
+	}
+}
@@ -0,0 +1,135 @@
+package reviser
+
+import (
+	"go/ast"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// DuplicateImport describes an import spec DedupeImports removed because
+// another spec already covered the same effective import.
+type DuplicateImport struct {
+	Path  string
+	Alias string
+}
+
+// WithDedupeImports enables duplicate-import detection: exact duplicates are
+// dropped, redundant aliases are collapsed, and blank/dot imports are kept
+// as distinct side effects rather than coalesced away.
+func WithDedupeImports() SourceFileOption {
+	return func(f *SourceFile) error {
+		f.shouldDedupeImports = true
+		return nil
+	}
+}
+
+// importSpecEntry is the path+alias+comments view of one import spec,
+// reconstructed from an importsWithMetadata key/value pair so dedupe logic
+// doesn't have to re-parse the "alias \"path\"" string format everywhere.
+type importSpecEntry struct {
+	alias string
+	path  string
+	meta  *commentsMetadata
+}
+
+// dedupeImports mirrors gofmt's sortImports semantics: blank ("_") and dot
+// (".") imports are kept as distinct side-effecting imports even when they
+// share a path, an alias that's redundant with the package's own name is
+// collapsed to an unaliased import, and exact duplicates are dropped,
+// merging their doc/line comments into the surviving spec.
+//
+// entries must be in source order (parseImports provides this) and must not
+// have been coalesced into a map yet - two specs sharing a key need to reach
+// here as two entries, or the second would silently win over the first
+// before dedup logic ever saw a duplicate. Walking entries in that fixed
+// source order, rather than ranging over a map, is also what makes which
+// spec survives - and therefore the merged comment order - deterministic
+// between runs on the same input.
+func (f *SourceFile) dedupeImports(
+	entries []rawImportEntry, packageImports map[string]string,
+) (map[string]*commentsMetadata, []DuplicateImport) {
+	type key struct {
+		path  string
+		alias string
+	}
+
+	order := make([]key, 0, len(entries))
+	byKey := map[key]importSpecEntry{}
+	var removed []DuplicateImport
+
+	for _, entry := range entries {
+		alias, quotedPath := splitImportSpecStr(entry.key)
+		entryPath := strings.Trim(quotedPath, `"`)
+
+		if alias != "_" && alias != "." && alias != "" {
+			if pkgName, ok := packageImports[entryPath]; ok {
+				if alias == pkgName {
+					alias = ""
+				}
+			} else if alias == path.Base(entryPath) {
+				alias = ""
+			}
+		}
+
+		k := key{path: entryPath, alias: alias}
+
+		if existing, ok := byKey[k]; ok {
+			existing.meta = &commentsMetadata{
+				Doc:     mergeCommentGroups(existing.meta.Doc, entry.meta.Doc),
+				Comment: mergeCommentGroups(existing.meta.Comment, entry.meta.Comment),
+			}
+			byKey[k] = existing
+			removed = append(removed, DuplicateImport{Path: entryPath, Alias: alias})
+			continue
+		}
+
+		byKey[k] = importSpecEntry{alias: alias, path: entryPath, meta: entry.meta}
+		order = append(order, k)
+	}
+
+	deduped := make(map[string]*commentsMetadata, len(order))
+	for _, k := range order {
+		entry := byKey[k]
+		deduped[importSpecStrFor(entry.alias, strconv.Quote(entry.path))] = entry.meta
+	}
+
+	return deduped, removed
+}
+
+// splitImportSpecStr splits an "alias \"path\"" (or bare "\"path\"") key, as
+// produced by parseImports, back into its alias and quoted path.
+func splitImportSpecStr(imprt string) (alias, quotedPath string) {
+	values := strings.SplitN(imprt, " ", 2)
+	if len(values) > 1 {
+		return values[0], values[1]
+	}
+
+	return "", values[0]
+}
+
+func importSpecStrFor(alias, quotedPath string) string {
+	if alias == "" {
+		return quotedPath
+	}
+
+	return strings.Join([]string{alias, quotedPath}, " ")
+}
+
+// mergeCommentGroups combines the doc/line comments of a dropped duplicate
+// spec into the comments of the spec that survives dedupe, instead of
+// silently discarding whichever one the map happened to overwrite.
+func mergeCommentGroups(into, from *ast.CommentGroup) *ast.CommentGroup {
+	if into == nil {
+		return from
+	}
+	if from == nil {
+		return into
+	}
+
+	merged := &ast.CommentGroup{List: make([]*ast.Comment, 0, len(into.List)+len(from.List))}
+	merged.List = append(merged.List, into.List...)
+	merged.List = append(merged.List, from.List...)
+
+	return merged
+}
@@ -0,0 +1,86 @@
+package reviser
+
+import (
+	"go/ast"
+	"testing"
+)
+
+func commentGroup(text string) *ast.CommentGroup {
+	return &ast.CommentGroup{List: []*ast.Comment{{Text: text}}}
+}
+
+func TestDedupeImportsMergesExactDuplicates(t *testing.T) {
+	sf := &SourceFile{shouldDedupeImports: true}
+
+	entries := []rawImportEntry{
+		{key: `"os"`, meta: &commentsMetadata{Doc: commentGroup("// first os import")}},
+		{key: `"os"`, meta: &commentsMetadata{Doc: commentGroup("// second os import")}},
+	}
+
+	deduped, removed := sf.dedupeImports(entries, nil)
+
+	if len(deduped) != 1 {
+		t.Fatalf("got %d surviving imports, want 1", len(deduped))
+	}
+
+	if len(removed) != 1 {
+		t.Fatalf("got %d removed duplicates, want 1", len(removed))
+	}
+
+	meta := deduped[`"os"`]
+	if meta == nil || meta.Doc == nil {
+		t.Fatal("surviving spec lost its doc comment")
+	}
+
+	if len(meta.Doc.List) != 2 {
+		t.Fatalf("got %d merged doc comments, want both originals kept (2)", len(meta.Doc.List))
+	}
+}
+
+func TestDedupeImportsIsDeterministic(t *testing.T) {
+	sf := &SourceFile{shouldDedupeImports: true}
+
+	entries := []rawImportEntry{
+		{key: `"os"`, meta: &commentsMetadata{Doc: commentGroup("// first")}},
+		{key: `"os"`, meta: &commentsMetadata{Doc: commentGroup("// second")}},
+		{key: `"os"`, meta: &commentsMetadata{Doc: commentGroup("// third")}},
+	}
+
+	var want string
+	for i := 0; i < 20; i++ {
+		deduped, _ := sf.dedupeImports(entries, nil)
+
+		var got string
+		for _, c := range deduped[`"os"`].Doc.List {
+			got += c.Text
+		}
+
+		if i == 0 {
+			want = got
+			continue
+		}
+
+		if got != want {
+			t.Fatalf("merged comment order changed between runs: %q != %q", got, want)
+		}
+	}
+}
+
+func TestDedupeImportsKeepsBlankAndDotDistinct(t *testing.T) {
+	sf := &SourceFile{shouldDedupeImports: true}
+
+	entries := []rawImportEntry{
+		{key: `_ "pkg"`, meta: &commentsMetadata{}},
+		{key: `. "pkg"`, meta: &commentsMetadata{}},
+	}
+
+	deduped, removed := sf.dedupeImports(entries, nil)
+
+	if len(deduped) != 2 {
+		t.Fatalf("got %d surviving imports, want 2 (blank and dot kept distinct)", len(deduped))
+	}
+
+	if len(removed) != 0 {
+		t.Fatalf("got %d removed duplicates, want 0", len(removed))
+	}
+}
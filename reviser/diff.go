@@ -0,0 +1,121 @@
+package reviser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnifiedDiff renders a minimal unified diff between original and revised,
+// labelled with filePath. It returns "" when the two have the same lines, so
+// callers can use it as a change-summary without a separate equality check.
+// Lines are compared without their final newline: inputs that differ only
+// in whether the last line ends with "\n" also give "".
+func UnifiedDiff(filePath string, original, revised []byte) string {
+	oldLines := splitLines(string(original))
+	newLines := splitLines(string(revised))
+
+	if linesEqual(oldLines, newLines) {
+		return ""
+	}
+
+	ops := diffLines(oldLines, newLines)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n", filePath)
+	fmt.Fprintf(&sb, "+++ b/%s\n", filePath)
+	fmt.Fprintf(&sb, "@@ -1,%d +1,%d @@\n", len(oldLines), len(newLines))
+
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			sb.WriteString(" " + op.line + "\n")
+		case diffRemove:
+			sb.WriteString("-" + op.line + "\n")
+		case diffAdd:
+			sb.WriteString("+" + op.line + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffRemove
+	diffAdd
+)
+
+type diffOp struct {
+	kind diffOpKind
+	line string
+}
+
+// diffLines computes a line-level diff between old and new via the longest
+// common subsequence, the same approach classic line-diff tools use.
+func diffLines(oldLines, newLines []string) []diffOp {
+	n, m := len(oldLines), len(newLines)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{kind: diffEqual, line: oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: diffRemove, line: oldLines[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: diffAdd, line: newLines[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{kind: diffRemove, line: oldLines[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{kind: diffAdd, line: newLines[j]})
+	}
+
+	return ops
+}
@@ -0,0 +1,140 @@
+package reviser
+
+import "testing"
+
+func opsString(ops []diffOp) []string {
+	out := make([]string, len(ops))
+	for i, op := range ops {
+		var prefix string
+		switch op.kind {
+		case diffEqual:
+			prefix = " "
+		case diffRemove:
+			prefix = "-"
+		case diffAdd:
+			prefix = "+"
+		}
+		out[i] = prefix + op.line
+	}
+	return out
+}
+
+func assertOps(t *testing.T, old, revised []string, want []string) {
+	t.Helper()
+
+	got := opsString(diffLines(old, revised))
+
+	if len(got) != len(want) {
+		t.Fatalf("diffLines(%v, %v) = %v, want %v", old, revised, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("diffLines(%v, %v) = %v, want %v", old, revised, got, want)
+		}
+	}
+}
+
+func TestDiffLinesInsertOnly(t *testing.T) {
+	assertOps(t,
+		[]string{"a", "c"},
+		[]string{"a", "b", "c"},
+		[]string{" a", "+b", " c"},
+	)
+}
+
+func TestDiffLinesDeleteOnly(t *testing.T) {
+	assertOps(t,
+		[]string{"a", "b", "c"},
+		[]string{"a", "c"},
+		[]string{" a", "-b", " c"},
+	)
+}
+
+func TestDiffLinesReplacesChangedLine(t *testing.T) {
+	assertOps(t,
+		[]string{"a", "b", "c"},
+		[]string{"a", "x", "c"},
+		[]string{" a", "-b", "+x", " c"},
+	)
+}
+
+func TestDiffLinesEmptyOld(t *testing.T) {
+	assertOps(t, nil, []string{"a", "b"}, []string{"+a", "+b"})
+}
+
+func TestDiffLinesEmptyNew(t *testing.T) {
+	assertOps(t, []string{"a", "b"}, nil, []string{"-a", "-b"})
+}
+
+func TestDiffLinesBothEmpty(t *testing.T) {
+	assertOps(t, nil, nil, nil)
+}
+
+func TestDiffLinesIdentical(t *testing.T) {
+	assertOps(t,
+		[]string{"a", "b"},
+		[]string{"a", "b"},
+		[]string{" a", " b"},
+	)
+}
+
+// applyOps reconstructs the new-side lines from a diffOp sequence, so a test
+// can check the diff is actually invertible without pinning down exactly
+// which lines LCS tie-breaking picked as "equal".
+func applyOps(ops []diffOp) []string {
+	var out []string
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual, diffAdd:
+			out = append(out, op.line)
+		}
+	}
+	return out
+}
+
+func TestDiffLinesReorderedBlocksReconstructsNew(t *testing.T) {
+	old := []string{"a", "b", "c"}
+	revised := []string{"c", "b", "a"}
+
+	ops := diffLines(old, revised)
+
+	got := applyOps(ops)
+	if len(got) != len(revised) {
+		t.Fatalf("applyOps(diffLines(%v, %v)) = %v, want %v", old, revised, got, revised)
+	}
+	for i := range got {
+		if got[i] != revised[i] {
+			t.Fatalf("applyOps(diffLines(%v, %v)) = %v, want %v", old, revised, got, revised)
+		}
+	}
+}
+
+func TestUnifiedDiffReturnsEmptyStringForIdenticalInput(t *testing.T) {
+	got := UnifiedDiff("file.go", []byte("a\nb\n"), []byte("a\nb\n"))
+	if got != "" {
+		t.Fatalf("UnifiedDiff = %q, want empty string for identical input", got)
+	}
+}
+
+func TestUnifiedDiffRendersHeaderAndHunk(t *testing.T) {
+	got := UnifiedDiff("file.go", []byte("a\nb\n"), []byte("a\nc\n"))
+
+	wantLines := []string{
+		"--- a/file.go",
+		"+++ b/file.go",
+		"@@ -1,2 +1,2 @@",
+		" a",
+		"-b",
+		"+c",
+	}
+
+	gotLines := splitLines(got)
+	if len(gotLines) != len(wantLines) {
+		t.Fatalf("UnifiedDiff lines = %v, want %v", gotLines, wantLines)
+	}
+	for i := range gotLines {
+		if gotLines[i] != wantLines[i] {
+			t.Fatalf("UnifiedDiff lines = %v, want %v", gotLines, wantLines)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package reviser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+)
+
+// importEditKind identifies which mutation a queued importEdit performs.
+type importEditKind int
+
+const (
+	importEditAdd importEditKind = iota
+	importEditDelete
+	importEditRewrite
+)
+
+// importEdit is a queued import mutation, staged by AddImport and friends
+// and applied directly to the parsed *ast.File inside FixBytes, before
+// fixImports groups and sorts the result.
+type importEdit struct {
+	kind    importEditKind
+	name    string
+	path    string
+	oldPath string
+}
+
+// AddImport queues path to be added as a plain (unnamed) import the next
+// time Fix or FixBytes runs. It's a no-op if path is already imported or
+// already queued, which keeps driving it from a codemod over many files
+// idempotent.
+func (f *SourceFile) AddImport(path string) (bool, error) {
+	return f.AddNamedImport("", path)
+}
+
+// AddNamedImport is AddImport with an explicit alias; pass "_" or "." to
+// queue a blank or dot import.
+func (f *SourceFile) AddNamedImport(name, path string) (bool, error) {
+	if path == "" {
+		return false, fmt.Errorf("reviser: import path must not be empty")
+	}
+
+	key := "add:" + name + ":" + path
+	if _, ok := f.stagedImportEdits[key]; ok {
+		return false, nil
+	}
+
+	f.stageImportEdit(key, importEdit{kind: importEditAdd, name: name, path: path})
+
+	return true, nil
+}
+
+// DeleteImport queues the removal of the plain import at path.
+func (f *SourceFile) DeleteImport(path string) (bool, error) {
+	return f.DeleteNamedImport("", path)
+}
+
+// DeleteNamedImport queues the removal of the import at path aliased as
+// name. Pass "_" or "." to target a blank or dot import specifically.
+func (f *SourceFile) DeleteNamedImport(name, path string) (bool, error) {
+	if path == "" {
+		return false, fmt.Errorf("reviser: import path must not be empty")
+	}
+
+	key := "delete:" + name + ":" + path
+	if _, ok := f.stagedImportEdits[key]; ok {
+		return false, nil
+	}
+
+	f.stageImportEdit(key, importEdit{kind: importEditDelete, name: name, path: path})
+
+	return true, nil
+}
+
+// RewriteImport queues replacing every import of oldPath with newPath,
+// keeping whatever alias (if any) the original spec used. Handy for
+// codemods like "context.Context migrated to golang.org/x/net/context/v2".
+func (f *SourceFile) RewriteImport(oldPath, newPath string) (bool, error) {
+	if oldPath == "" || newPath == "" {
+		return false, fmt.Errorf("reviser: import paths must not be empty")
+	}
+
+	if oldPath == newPath {
+		return false, nil
+	}
+
+	key := "rewrite:" + oldPath + ":" + newPath
+	if _, ok := f.stagedImportEdits[key]; ok {
+		return false, nil
+	}
+
+	f.stageImportEdit(key, importEdit{kind: importEditRewrite, path: newPath, oldPath: oldPath})
+
+	return true, nil
+}
+
+func (f *SourceFile) stageImportEdit(key string, edit importEdit) {
+	if f.stagedImportEdits == nil {
+		f.stagedImportEdits = map[string]struct{}{}
+	}
+
+	f.stagedImportEdits[key] = struct{}{}
+	f.pendingImportEdits = append(f.pendingImportEdits, edit)
+}
+
+// applyImportEdits mutates pf's import declarations in place, applying every
+// queued AddImport/DeleteImport/RewriteImport call before fixImports groups
+// and sorts the result.
+func (f *SourceFile) applyImportEdits(pf *ast.File) {
+	for _, edit := range f.pendingImportEdits {
+		switch edit.kind {
+		case importEditAdd:
+			addImportSpec(pf, edit.name, edit.path)
+		case importEditDelete:
+			deleteImportSpec(pf, edit.name, edit.path)
+		case importEditRewrite:
+			rewriteImportSpec(pf, edit.oldPath, edit.path)
+		}
+	}
+}
+
+func importGenDecl(pf *ast.File) *ast.GenDecl {
+	for _, decl := range pf.Decls {
+		if dd, ok := decl.(*ast.GenDecl); ok && dd.Tok == token.IMPORT && !isSingleCgoImport(dd) {
+			return dd
+		}
+	}
+
+	return nil
+}
+
+// addImportSpec appends name/path to pf's import block, creating one if the
+// file had none, and dedupes against any existing spec for the same path and
+// alias - checking pf.Imports, which spans every import declaration in the
+// file, not just the first one a multi-import-block file happens to have.
+func addImportSpec(pf *ast.File, name, path string) bool {
+	quoted := strconv.Quote(path)
+
+	for _, imp := range pf.Imports {
+		if imp.Path.Value == quoted && importSpecNameEquals(imp, name) {
+			return false
+		}
+	}
+
+	dd := importGenDecl(pf)
+	if dd == nil {
+		dd = &ast.GenDecl{Tok: token.IMPORT, Lparen: 1}
+		pf.Decls = append([]ast.Decl{dd}, pf.Decls...)
+	}
+
+	spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: quoted}}
+	if name != "" {
+		spec.Name = ast.NewIdent(name)
+	}
+
+	dd.Specs = append(dd.Specs, spec)
+	pf.Imports = append(pf.Imports, spec)
+
+	return true
+}
+
+// deleteImportSpec removes every import spec across pf's import
+// declarations matching name/path.
+func deleteImportSpec(pf *ast.File, name, path string) bool {
+	quoted := strconv.Quote(path)
+	var removed bool
+
+	for _, decl := range pf.Decls {
+		dd, ok := decl.(*ast.GenDecl)
+		if !ok || dd.Tok != token.IMPORT || isSingleCgoImport(dd) {
+			continue
+		}
+
+		specs := dd.Specs[:0]
+		for _, spec := range dd.Specs {
+			imp := spec.(*ast.ImportSpec)
+			if imp.Path.Value == quoted && importSpecNameEquals(imp, name) {
+				removed = true
+				continue
+			}
+			specs = append(specs, spec)
+		}
+		dd.Specs = specs
+	}
+
+	if removed {
+		imports := pf.Imports[:0]
+		for _, imp := range pf.Imports {
+			if imp.Path.Value == quoted && importSpecNameEquals(imp, name) {
+				continue
+			}
+			imports = append(imports, imp)
+		}
+		pf.Imports = imports
+	}
+
+	return removed
+}
+
+// rewriteImportSpec replaces the path of every import spec matching oldPath
+// with newPath, leaving any alias untouched.
+func rewriteImportSpec(pf *ast.File, oldPath, newPath string) bool {
+	quotedOld := strconv.Quote(oldPath)
+	quotedNew := strconv.Quote(newPath)
+	var rewritten bool
+
+	for _, spec := range pf.Imports {
+		if spec.Path.Value == quotedOld {
+			spec.Path.Value = quotedNew
+			rewritten = true
+		}
+	}
+
+	return rewritten
+}
+
+func importSpecNameEquals(imp *ast.ImportSpec, name string) bool {
+	if imp.Name == nil {
+		return name == ""
+	}
+
+	return imp.Name.Name == name
+}
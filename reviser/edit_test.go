@@ -0,0 +1,86 @@
+package reviser
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+const multiImportDeclSource = `package main
+
+import (
+	"fmt"
+)
+
+import (
+	"os"
+)
+
+func main() {
+	fmt.Println(os.Args)
+}
+`
+
+func TestAddImportSpecDedupesAcrossMultipleImportDecls(t *testing.T) {
+	fset := token.NewFileSet()
+	pf, err := parser.ParseFile(fset, "", multiImportDeclSource, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	if len(pf.Decls) < 2 {
+		t.Fatalf("test source must have at least two decls, got %d", len(pf.Decls))
+	}
+
+	before := len(pf.Imports)
+
+	// "os" is only present in the second import declaration; addImportSpec
+	// must dedupe against it even though importGenDecl only returns the
+	// first one.
+	added := addImportSpec(pf, "", "os")
+	if added {
+		t.Fatal("addImportSpec reported adding \"os\", want a no-op since it's already imported in a later decl")
+	}
+
+	if len(pf.Imports) != before {
+		t.Fatalf("got %d imports, want %d (no duplicate added)", len(pf.Imports), before)
+	}
+}
+
+func TestAddImportSpecAddsNewImport(t *testing.T) {
+	fset := token.NewFileSet()
+	pf, err := parser.ParseFile(fset, "", multiImportDeclSource, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	before := len(pf.Imports)
+
+	added := addImportSpec(pf, "", "strings")
+	if !added {
+		t.Fatal("addImportSpec reported a no-op for a genuinely new import")
+	}
+
+	if len(pf.Imports) != before+1 {
+		t.Fatalf("got %d imports, want %d", len(pf.Imports), before+1)
+	}
+}
+
+func TestDeleteImportSpecRemovesAcrossMultipleImportDecls(t *testing.T) {
+	fset := token.NewFileSet()
+	pf, err := parser.ParseFile(fset, "", multiImportDeclSource, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	removed := deleteImportSpec(pf, "", "os")
+	if !removed {
+		t.Fatal("deleteImportSpec reported not removing \"os\", want it removed from the second import decl")
+	}
+
+	for _, imp := range pf.Imports {
+		if imp.Path.Value == `"os"` {
+			t.Fatal("\"os\" import still present after deleteImportSpec")
+		}
+	}
+}
@@ -12,11 +12,9 @@ import (
 	"os"
 	"path"
 	"regexp"
-	"sort"
 	"strings"
 
 	"github.com/incu6us/goimports-reviser/v3/pkg/astutil"
-	"github.com/incu6us/goimports-reviser/v3/pkg/std"
 )
 
 const (
@@ -34,11 +32,38 @@ type SourceFile struct {
 	shouldUseAliasForVersionSuffix bool
 	shouldFormatCode               bool
 	shouldSkipAutoGenerated        bool
+	shouldDedupeImports            bool
 	companyPackagePrefixes         []string
-	importsOrders                  ImportsOrders
+	importGroups                   []ImportGroup
 
 	projectName string
 	filePath    string
+
+	pendingImportEdits []importEdit
+	stagedImportEdits  map[string]struct{}
+
+	removedDuplicateImports []DuplicateImport
+	changeLog               []Change
+
+	dependencyResolver PackageDependencyResolver
+}
+
+// loadPackageDependencies resolves dir's import-path -> package-name map,
+// going through dependencyResolver when a Batch has injected a shared,
+// cached one, and falling back to astutil directly otherwise.
+func (f *SourceFile) loadPackageDependencies(dir, buildTag string) (map[string]string, error) {
+	if f.dependencyResolver != nil {
+		return f.dependencyResolver.LoadPackageDependencies(dir, buildTag)
+	}
+
+	return astutil.LoadPackageDependencies(dir, buildTag)
+}
+
+// RemovedDuplicateImports reports the duplicate imports DedupeImports
+// dropped during the most recent Fix/FixBytes call, so callers can log which
+// specs were collapsed.
+func (f *SourceFile) RemovedDuplicateImports() []DuplicateImport {
+	return f.removedDuplicateImports
 }
 
 // NewSourceFile constructor
@@ -69,34 +94,61 @@ func (f *SourceFile) Fix(options ...SourceFileOption) ([]byte, bool, error) {
 		return nil, false, err
 	}
 
+	return f.FixBytes(originalContent)
+}
+
+// FixBytes revises and formats src in memory, without touching the
+// filesystem or stdin. Fix is a thin wrapper around it for the common
+// read-a-path case; calling FixBytes directly lets a caller who already has
+// source in memory (or who wants to call AddImport/DeleteImport/
+// RewriteImport first) skip the round trip through disk.
+func (f *SourceFile) FixBytes(src []byte, options ...SourceFileOption) ([]byte, bool, error) {
+	for _, option := range options {
+		err := option(f)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	f.changeLog = nil
+	f.removedDuplicateImports = nil
+
 	fset := token.NewFileSet()
 
-	pf, err := parser.ParseFile(fset, "", originalContent, parser.ParseComments)
+	pf, err := parser.ParseFile(fset, "", src, parser.ParseComments)
 	if err != nil {
 		return nil, false, err
 	}
 
 	if f.shouldSkipAutoGenerated && isFileAutoGenerate(pf) {
-		return originalContent, false, nil
+		return src, false, nil
 	}
 
+	f.applyImportEdits(pf)
+
 	importsWithMetadata, err := f.parseImports(pf)
 	if err != nil {
 		return nil, false, err
 	}
 
-	stdImports, generalImports, namedImports, projectLocalPkgs, projectImports := groupImports(
-		f.projectName,
-		f.companyPackagePrefixes,
-		importsWithMetadata,
-	)
+	groups := f.importGroups
+	if len(groups) == 0 {
+		groups = DefaultImportGroups(f.projectName, f.companyPackagePrefixes)
+	}
+
+	groupedImports, err := groupImportsByGroups(groups, importsWithMetadata)
+	if err != nil {
+		return nil, false, err
+	}
+
+	f.recordChanges(groups, importsWithMetadata)
 
 	decls, ok := hasMultipleImportDecls(pf)
 	if ok {
 		pf.Decls = decls
 	}
 
-	f.fixImports(pf, stdImports, generalImports, namedImports, projectLocalPkgs, projectImports, importsWithMetadata)
+	f.fixImports(pf, groupedImports, importsWithMetadata)
 
 	f.formatDecls(pf)
 
@@ -110,7 +162,7 @@ func (f *SourceFile) Fix(options ...SourceFileOption) ([]byte, bool, error) {
 		return nil, false, err
 	}
 
-	return formattedContent, !bytes.Equal(originalContent, formattedContent), nil
+	return formattedContent, !bytes.Equal(src, formattedContent), nil
 }
 
 func isFileAutoGenerate(pf *ast.File) bool {
@@ -157,74 +209,6 @@ func fixCommentGroup(commentGroup *ast.CommentGroup) *ast.CommentGroup {
 	return formattedDoc
 }
 
-func groupImports(
-	projectName string,
-	localPkgPrefixes []string,
-	importsWithMetadata map[string]*commentsMetadata,
-) ([]string, []string, []string, []string, []string) {
-	var (
-		stdImports       []string
-		projectImports   []string
-		projectLocalPkgs []string
-		namedImports     []string
-		generalImports   []string
-	)
-
-	for imprt := range importsWithMetadata {
-		values := strings.Split(imprt, " ")
-		if len(values) > 1 {
-			namedImports = append(namedImports, imprt)
-			continue
-		}
-
-		pkgWithoutAlias := skipPackageAlias(imprt)
-
-		if _, ok := std.StdPackages[pkgWithoutAlias]; ok {
-			stdImports = append(stdImports, imprt)
-			continue
-		}
-
-		var isLocalPackageFound bool
-		for _, localPackagePrefix := range localPkgPrefixes {
-			fmt.Printf("pkgWithoutAlias: %s localPackagePrefix: %s\n", pkgWithoutAlias, localPackagePrefix)
-			if strings.HasPrefix(pkgWithoutAlias, localPackagePrefix) { // && !strings.HasPrefix(pkgWithoutAlias, projectName) {
-				fmt.Printf("local package found: %s\n", imprt)
-				projectLocalPkgs = append(projectLocalPkgs, imprt)
-				isLocalPackageFound = true
-				break
-			}
-		}
-
-		if isLocalPackageFound {
-			continue
-		}
-
-		if strings.Contains(pkgWithoutAlias, projectName) {
-			projectImports = append(projectImports, imprt)
-			continue
-		}
-
-		generalImports = append(generalImports, imprt)
-	}
-
-	sort.Strings(stdImports)
-	sort.Strings(generalImports)
-	sort.Strings(namedImports)
-	sort.Strings(projectLocalPkgs)
-	sort.Strings(projectImports)
-
-	return stdImports, generalImports, namedImports, projectLocalPkgs, projectImports
-}
-
-func skipPackageAlias(pkg string) string {
-	values := strings.Split(pkg, " ")
-	if len(values) > 1 {
-		return strings.Trim(values[1], `"`)
-	}
-
-	return strings.Trim(pkg, `"`)
-}
-
 func generateFile(fset *token.FileSet, f *ast.File) ([]byte, error) {
 	var output []byte
 	buffer := bytes.NewBuffer(output)
@@ -247,7 +231,7 @@ func isSingleCgoImport(dd *ast.GenDecl) bool {
 
 func (f *SourceFile) fixImports(
 	file *ast.File,
-	stdImports, generalImports, namedImports, projectLocalPkgs, projectImports []string,
+	groupedImports [][]string,
 	commentsMetadata map[string]*commentsMetadata,
 ) {
 	var importsPositions []*importPosition
@@ -268,9 +252,7 @@ func (f *SourceFile) fixImports(
 			},
 		)
 
-		fmt.Printf("named: %v\n", namedImports)
-		one, two, three, four, five := f.importsOrders.sortImportsByOrder(stdImports, generalImports, namedImports, projectLocalPkgs, projectImports)
-		dd.Specs = rebuildImports(dd.Tok, commentsMetadata, one, two, three, four, five)
+		dd.Specs = rebuildImports(dd.Tok, commentsMetadata, groupedImports)
 	}
 
 	clearImportDocs(file, importsPositions)
@@ -362,86 +344,38 @@ func removeEmptyImportNode(f *ast.File) {
 	}
 }
 
+// rebuildImports lays specs back out group by group, in the order
+// groupedImports is given in, inserting a blank spec between any two
+// non-empty groups so gofmt keeps them visually separated.
 func rebuildImports(
 	tok token.Token,
 	commentsMetadata map[string]*commentsMetadata,
-	firstImportGroup []string,
-	secondImportsGroup []string,
-	thirdImportsGroup []string,
-	fourthImportGroup []string,
-	fifthImportGroup []string,
+	groupedImports [][]string,
 ) []ast.Spec {
 	var specs []ast.Spec
 
-	linesCounter := len(firstImportGroup)
-	for _, imprt := range firstImportGroup {
-		spec := &ast.ImportSpec{
-			Path: &ast.BasicLit{Value: importWithComment(imprt, commentsMetadata), Kind: tok},
-		}
-		specs = append(specs, spec)
-
-		linesCounter--
-
-		if linesCounter == 0 && (len(secondImportsGroup) > 0 || len(thirdImportsGroup) > 0 || len(fourthImportGroup) > 0) {
-			spec = &ast.ImportSpec{Path: &ast.BasicLit{Value: "", Kind: token.STRING}}
-
-			specs = append(specs, spec)
-		}
-	}
-
-	linesCounter = len(secondImportsGroup)
-	for _, imprt := range secondImportsGroup {
-		spec := &ast.ImportSpec{
-			Path: &ast.BasicLit{Value: importWithComment(imprt, commentsMetadata), Kind: tok},
-		}
-		specs = append(specs, spec)
-
-		linesCounter--
-
-		if linesCounter == 0 && (len(thirdImportsGroup) > 0 || len(fourthImportGroup) > 0) {
-			spec = &ast.ImportSpec{Path: &ast.BasicLit{Value: "", Kind: token.STRING}}
-
-			specs = append(specs, spec)
-		}
-	}
-
-	linesCounter = len(thirdImportsGroup)
-	for _, imprt := range thirdImportsGroup {
-		spec := &ast.ImportSpec{
-			Path: &ast.BasicLit{Value: importWithComment(imprt, commentsMetadata), Kind: tok},
-		}
-		specs = append(specs, spec)
-
-		linesCounter--
-
-		if linesCounter == 0 && len(fourthImportGroup) > 0 {
-			spec = &ast.ImportSpec{Path: &ast.BasicLit{Value: "", Kind: token.STRING}}
-
-			specs = append(specs, spec)
+	remainingNonEmptyGroups := 0
+	for _, group := range groupedImports {
+		if len(group) > 0 {
+			remainingNonEmptyGroups++
 		}
 	}
 
-	linesCounter = len(fourthImportGroup)
-	for _, imprt := range fourthImportGroup {
-		spec := &ast.ImportSpec{
-			Path: &ast.BasicLit{Value: importWithComment(imprt, commentsMetadata), Kind: tok},
+	for _, group := range groupedImports {
+		if len(group) == 0 {
+			continue
 		}
-		specs = append(specs, spec)
-
-		linesCounter--
-
-		if linesCounter == 0 && len(fourthImportGroup) > 0 {
-			spec = &ast.ImportSpec{Path: &ast.BasicLit{Value: "", Kind: token.STRING}}
 
-			specs = append(specs, spec)
+		for _, imprt := range group {
+			specs = append(specs, &ast.ImportSpec{
+				Path: &ast.BasicLit{Value: importWithComment(imprt, commentsMetadata), Kind: tok},
+			})
 		}
-	}
 
-	for _, imprt := range fifthImportGroup {
-		spec := &ast.ImportSpec{
-			Path: &ast.BasicLit{Value: importWithComment(imprt, commentsMetadata), Kind: tok},
+		remainingNonEmptyGroups--
+		if remainingNonEmptyGroups > 0 {
+			specs = append(specs, &ast.ImportSpec{Path: &ast.BasicLit{Value: "", Kind: token.STRING}})
 		}
-		specs = append(specs, spec)
 	}
 
 	return specs
@@ -464,15 +398,36 @@ func clearImportDocs(f *ast.File, importsPositions []*importPosition) {
 	}
 }
 
+// importWithComment re-attaches the doc (leading) and line (trailing)
+// comments a spec carried before regrouping, keyed by the same path+alias
+// string used in commentsMetadata. Doc comments cover things like
+// "// Deprecated: use foo/v2" or a "// BUG(user): ..." marker sitting
+// directly above an import spec, which gofmt-style comment handling would
+// otherwise lose whenever the import moves groups; embedding them ahead of
+// the path here means they survive the printer.Fprint -> format.Source
+// round trip the same way the trailing comment already did.
 func importWithComment(imprt string, commentsMetadata map[string]*commentsMetadata) string {
+	meta, ok := commentsMetadata[imprt]
+	if !ok || meta == nil {
+		return imprt
+	}
+
+	var doc string
+	if meta.Doc != nil {
+		for _, c := range meta.Doc.List {
+			doc += c.Text + "\n"
+		}
+	}
+
 	var comment string
-	commentGroup, ok := commentsMetadata[imprt]
-	if ok && commentGroup != nil && commentGroup.Comment != nil {
-		for _, c := range commentGroup.Comment.List {
+	if meta.Comment != nil {
+		for _, c := range meta.Comment.List {
 			comment += c.Text
 		}
 	}
 
+	imprt = doc + imprt
+
 	if comment == "" {
 		return imprt
 	}
@@ -480,22 +435,32 @@ func importWithComment(imprt string, commentsMetadata map[string]*commentsMetada
 	return fmt.Sprintf("%s %s", imprt, comment)
 }
 
-func (f *SourceFile) parseImports(file *ast.File) (map[string]*commentsMetadata, error) {
-	importsWithMetadata := map[string]*commentsMetadata{}
+// rawImportEntry is one import spec as encountered in source order, before
+// any key-based coalescing. Keeping these as a slice (rather than writing
+// straight into a map) is what lets dedupeImports see two specs that happen
+// to share the same key instead of the second silently overwriting the
+// first.
+type rawImportEntry struct {
+	key  string
+	meta *commentsMetadata
+}
 
+func (f *SourceFile) parseImports(file *ast.File) (map[string]*commentsMetadata, error) {
 	shouldRemoveUnusedImports := f.shouldRemoveUnusedImports
 	shouldUseAliasForVersionSuffix := f.shouldUseAliasForVersionSuffix
 
 	var packageImports map[string]string
 	var err error
 
-	if shouldRemoveUnusedImports || shouldUseAliasForVersionSuffix {
-		packageImports, err = astutil.LoadPackageDependencies(path.Dir(f.filePath), astutil.ParseBuildTag(file))
+	if shouldRemoveUnusedImports || shouldUseAliasForVersionSuffix || f.shouldDedupeImports {
+		packageImports, err = f.loadPackageDependencies(path.Dir(f.filePath), astutil.ParseBuildTag(file))
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	var rawEntries []rawImportEntry
+
 	for _, decl := range file.Decls {
 		switch decl.(type) {
 		case *ast.GenDecl:
@@ -524,15 +489,29 @@ func (f *SourceFile) parseImports(file *ast.File) (map[string]*commentsMetadata,
 						}
 					}
 
-					importsWithMetadata[importSpecStr] = &commentsMetadata{
-						Doc:     importSpec.Doc,
-						Comment: importSpec.Comment,
-					}
+					rawEntries = append(rawEntries, rawImportEntry{
+						key: importSpecStr,
+						meta: &commentsMetadata{
+							Doc:     importSpec.Doc,
+							Comment: importSpec.Comment,
+						},
+					})
 				}
 			}
 		}
 	}
 
+	if f.shouldDedupeImports {
+		deduped, removed := f.dedupeImports(rawEntries, packageImports)
+		f.removedDuplicateImports = removed
+		return deduped, nil
+	}
+
+	importsWithMetadata := make(map[string]*commentsMetadata, len(rawEntries))
+	for _, entry := range rawEntries {
+		importsWithMetadata[entry.key] = entry.meta
+	}
+
 	return importsWithMetadata, nil
 }
 
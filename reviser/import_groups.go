@@ -0,0 +1,191 @@
+package reviser
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/incu6us/goimports-reviser/v3/pkg/std"
+)
+
+// ImportGroupMatchKind selects how an ImportGroup's Pattern is interpreted.
+type ImportGroupMatchKind int
+
+const (
+	// MatchStd matches any package from the Go standard library; Pattern is ignored.
+	MatchStd ImportGroupMatchKind = iota
+	// MatchNamed matches any import with a non-blank, non-dot alias; Pattern is ignored.
+	MatchNamed
+	// MatchPrefix matches import paths with the literal prefix in Pattern.
+	MatchPrefix
+	// MatchGlob matches import paths against the glob in Pattern, where * and
+	// ? match any run of characters (including "/") and any single character
+	// respectively - unlike path.Match, a one-segment pattern like "k8s.io/*"
+	// covers the whole "k8s.io/..." subtree, not just one path segment below it.
+	MatchGlob
+	// MatchRegexp matches import paths against the compiled regexp in Pattern.
+	MatchRegexp
+	// MatchDefault matches anything not claimed by an earlier group. Exactly
+	// one group should use it; it's implicitly appended as the last group if
+	// the caller didn't supply one.
+	MatchDefault
+)
+
+// ImportGroup is one ordered bucket of imports. Groups are matched against
+// each import in slice order, first match wins, and are rendered in the
+// same order with a blank line between any two non-empty groups - replacing
+// the tool's old fixed five-bucket layout with something monorepos can
+// configure for themselves, e.g.:
+//
+//	std -> golang.org/x -> k8s.io/* -> github.com/ourorg/shared -> github.com/ourorg/thisrepo -> everything else
+type ImportGroup struct {
+	Name    string
+	Kind    ImportGroupMatchKind
+	Pattern string
+}
+
+// DefaultImportGroups builds the ordered group list used when no
+// WithImportGroups option is given, from the project name and local package
+// prefixes: named, std, one "projectlocal:<prefix>" group per prefix,
+// project (projectName as a path prefix), then general.
+//
+// named is checked before std, matching the original classifier's
+// alias-first precedence: an aliased std import like myfmt "fmt" is bucketed
+// as named, not std. Blank and dot imports are not bucketed as named.
+//
+// Classification is a single first-match-wins pass and groups are rendered
+// in that same order, so named imports come first and the catch-all
+// "general" bucket comes last in the output. This is not the same layout as
+// the tool's earlier fixed buckets, which rendered general second.
+func DefaultImportGroups(projectName string, localPkgPrefixes []string) []ImportGroup {
+	groups := []ImportGroup{
+		{Name: "named", Kind: MatchNamed},
+		{Name: "std", Kind: MatchStd},
+	}
+
+	for _, prefix := range localPkgPrefixes {
+		groups = append(groups, ImportGroup{Name: "projectlocal:" + prefix, Kind: MatchPrefix, Pattern: prefix})
+	}
+
+	if projectName != "" {
+		groups = append(groups, ImportGroup{Name: "project", Kind: MatchPrefix, Pattern: projectName})
+	}
+
+	groups = append(groups, ImportGroup{Name: "general", Kind: MatchDefault})
+
+	return groups
+}
+
+// match reports whether imprt - an "alias \"path\"" or bare "\"path\""
+// import key, as produced by parseImports - belongs to this group.
+func (g ImportGroup) match(imprt string) (bool, error) {
+	alias, quotedPath := splitImportSpecStr(imprt)
+	pkgWithoutAlias := strings.Trim(quotedPath, `"`)
+
+	switch g.Kind {
+	case MatchStd:
+		_, ok := std.StdPackages[pkgWithoutAlias]
+		return ok, nil
+	case MatchNamed:
+		return alias != "" && alias != "_" && alias != ".", nil
+	case MatchPrefix:
+		return strings.HasPrefix(pkgWithoutAlias, g.Pattern), nil
+	case MatchGlob:
+		re, err := globToRegexp(g.Pattern)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(pkgWithoutAlias), nil
+	case MatchRegexp:
+		re, err := regexp.Compile(g.Pattern)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(pkgWithoutAlias), nil
+	case MatchDefault:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// globToRegexp compiles pattern into an anchored regexp where * matches any
+// run of characters (including "/") and ? matches any single character, so
+// patterns like "k8s.io/*" cover a whole import subtree rather than just one
+// path segment below the prefix. A trailing "/*" also matches the bare
+// prefix itself with no subtree at all (e.g. "k8s.io/*" matches "k8s.io"),
+// the same way a directory glob covers both the directory and its contents.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	runes := []rune(pattern)
+
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i, r := range runes {
+		switch {
+		case r == '*' && i == len(runes)-1 && i > 0 && runes[i-1] == '/':
+			trimmed := strings.TrimSuffix(sb.String(), "/")
+			sb.Reset()
+			sb.WriteString(trimmed)
+			sb.WriteString("(/.*)?")
+		case r == '*':
+			sb.WriteString(".*")
+		case r == '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+// groupImportsByGroups assigns every import to the first ImportGroup that
+// matches it, sorting each resulting bucket and returning the buckets in
+// group order. If groups has no MatchDefault entry, one is appended so an
+// import that matches nothing still lands somewhere instead of being
+// dropped.
+func groupImportsByGroups(groups []ImportGroup, importsWithMetadata map[string]*commentsMetadata) ([][]string, error) {
+	hasDefault := false
+	for _, g := range groups {
+		if g.Kind == MatchDefault {
+			hasDefault = true
+			break
+		}
+	}
+	if !hasDefault {
+		groups = append(groups, ImportGroup{Name: "general", Kind: MatchDefault})
+	}
+
+	buckets := make([][]string, len(groups))
+
+	for imprt := range importsWithMetadata {
+		for i, group := range groups {
+			matched, err := group.match(imprt)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				buckets[i] = append(buckets[i], imprt)
+				break
+			}
+		}
+	}
+
+	for _, bucket := range buckets {
+		sort.Strings(bucket)
+	}
+
+	return buckets, nil
+}
+
+// WithImportGroups overrides the default std/named/project-local/project/
+// general buckets with a caller-supplied ordered group list.
+func WithImportGroups(groups []ImportGroup) SourceFileOption {
+	return func(f *SourceFile) error {
+		f.importGroups = groups
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package reviser
+
+import "testing"
+
+func TestImportGroupMatchGlobSpansSegments(t *testing.T) {
+	group := ImportGroup{Name: "k8s", Kind: MatchGlob, Pattern: "k8s.io/*"}
+
+	for _, pkg := range []string{
+		`"k8s.io/client-go/kubernetes"`,
+		`"k8s.io/api/core/v1"`,
+		`"k8s.io"`,
+	} {
+		matched, err := group.match(pkg)
+		if err != nil {
+			t.Fatalf("match(%s): unexpected error: %v", pkg, err)
+		}
+		if !matched {
+			t.Errorf("match(%s) = false, want true", pkg)
+		}
+	}
+
+	matched, err := group.match(`"github.com/other/pkg"`)
+	if err != nil {
+		t.Fatalf("match: unexpected error: %v", err)
+	}
+	if matched {
+		t.Error("match(github.com/other/pkg) = true, want false")
+	}
+}
+
+func TestGroupImportsByGroupsUsesGlobSubtree(t *testing.T) {
+	groups := []ImportGroup{
+		{Name: "std", Kind: MatchStd},
+		{Name: "k8s", Kind: MatchGlob, Pattern: "k8s.io/*"},
+		{Name: "general", Kind: MatchDefault},
+	}
+
+	importsWithMetadata := map[string]*commentsMetadata{
+		`"k8s.io/client-go/kubernetes"`: {},
+		`"k8s.io/api/core/v1"`:          {},
+		`"fmt"`:                         {},
+	}
+
+	buckets, err := groupImportsByGroups(groups, importsWithMetadata)
+	if err != nil {
+		t.Fatalf("groupImportsByGroups: unexpected error: %v", err)
+	}
+
+	if len(buckets) != 3 {
+		t.Fatalf("got %d buckets, want 3", len(buckets))
+	}
+
+	if len(buckets[1]) != 2 {
+		t.Errorf("k8s bucket = %v, want both k8s.io imports", buckets[1])
+	}
+
+	if len(buckets[2]) != 0 {
+		t.Errorf("general bucket = %v, want empty (k8s.io imports should not fall through)", buckets[2])
+	}
+}
+
+func TestDefaultImportGroupsClassifiesAliasedStdImportAsNamed(t *testing.T) {
+	groups := DefaultImportGroups("", nil)
+
+	importsWithMetadata := map[string]*commentsMetadata{
+		`myfmt "fmt"`: {},
+		`"os"`:        {},
+	}
+
+	buckets, err := groupImportsByGroups(groups, importsWithMetadata)
+	if err != nil {
+		t.Fatalf("groupImportsByGroups: unexpected error: %v", err)
+	}
+
+	namedIdx, stdIdx := -1, -1
+	for i, g := range groups {
+		switch g.Name {
+		case "named":
+			namedIdx = i
+		case "std":
+			stdIdx = i
+		}
+	}
+
+	if namedIdx == -1 || stdIdx == -1 {
+		t.Fatalf("expected both a named and a std group, got %v", groups)
+	}
+
+	found := false
+	for _, imprt := range buckets[namedIdx] {
+		if imprt == `myfmt "fmt"` {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf(`named bucket = %v, want it to contain myfmt "fmt"`, buckets[namedIdx])
+	}
+
+	for _, imprt := range buckets[stdIdx] {
+		if imprt == `myfmt "fmt"` {
+			t.Error(`std bucket contains myfmt "fmt", want aliased std imports classified as named`)
+		}
+	}
+
+	stdFound := false
+	for _, imprt := range buckets[stdIdx] {
+		if imprt == `"os"` {
+			stdFound = true
+		}
+	}
+	if !stdFound {
+		t.Errorf(`std bucket = %v, want it to contain "os"`, buckets[stdIdx])
+	}
+}
@@ -0,0 +1,78 @@
+package reviser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const reportTestSource = `package main
+
+import (
+	"fmt"
+
+	// Deprecated: use bar instead.
+	"foo"
+)
+
+func main() {
+	fmt.Println("hi")
+}
+`
+
+func TestFixWithReportDoesNotDuplicateAcrossCalls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "source.go")
+	if err := os.WriteFile(path, []byte(reportTestSource), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	sf := NewSourceFile("", path)
+
+	_, firstReport, err := sf.FixWithReport()
+	if err != nil {
+		t.Fatalf("FixWithReport: %v", err)
+	}
+	firstLen := len(firstReport.Changes)
+
+	_, secondReport, err := sf.FixWithReport()
+	if err != nil {
+		t.Fatalf("FixWithReport (again): %v", err)
+	}
+
+	if len(secondReport.Changes) != firstLen {
+		t.Fatalf("got %d changes after calling FixWithReport twice, want %d (no accumulation)", len(secondReport.Changes), firstLen)
+	}
+}
+
+func TestRecordChangesCommentKeptOrderIsDeterministic(t *testing.T) {
+	importsWithMetadata := map[string]*commentsMetadata{
+		`"zzz"`: {Doc: commentGroup("// z")},
+		`"aaa"`: {Doc: commentGroup("// a")},
+		`"mmm"`: {Doc: commentGroup("// m")},
+	}
+
+	var want []string
+	for i := 0; i < 20; i++ {
+		sf := &SourceFile{}
+		sf.recordChanges(nil, importsWithMetadata)
+
+		var got []string
+		for _, c := range sf.changeLog {
+			got = append(got, c.Path)
+		}
+
+		if i == 0 {
+			want = got
+			continue
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("run %d: got %d changes, want %d", i, len(got), len(want))
+		}
+		for j := range got {
+			if got[j] != want[j] {
+				t.Fatalf("run %d: ChangeCommentKept order changed: %v != %v", i, got, want)
+			}
+		}
+	}
+}